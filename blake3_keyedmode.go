@@ -1,8 +1,6 @@
 package passcode
 
 import (
-	"crypto/sha256"
-
 	"lukechampine.com/blake3"
 )
 
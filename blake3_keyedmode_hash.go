@@ -0,0 +1,26 @@
+package passcode
+
+import (
+	"hash"
+	"io"
+
+	"lukechampine.com/blake3"
+)
+
+type blake3KeyedHash struct {
+	*blake3.Hasher
+}
+
+// NewBLAKE3Keyed returns a streaming BLAKE3 keyed-mode hash.Hash (also an
+// XOFHash), so large messages can be written in chunks instead of
+// materialized up front.
+func NewBLAKE3Keyed(key []byte, outputLen int) hash.Hash {
+	hashedKey := blake3.Sum256(key)
+	return &blake3KeyedHash{blake3.New(outputLen, hashedKey[:])}
+}
+
+// XOF squeezes additional output from the current message beyond Size(),
+// mirroring BLAKE3's native extendable-output support.
+func (h *blake3KeyedHash) XOF() io.Reader {
+	return h.Hasher.XOF()
+}
@@ -0,0 +1,259 @@
+// Package bmt implements a binary Merkle tree MAC over a single chunk of up
+// to a fixed chunkSize, keyed via any algorithm selectable through
+// passcode.NewPasscode. It is modeled on the Swarm BMT hasher: the chunk's
+// payload is split into fixed-size segments, each segment is MACed into a
+// leaf, and leaves are paired and MACed upward until a single root remains.
+// A Tree covers only one chunkSize-bounded chunk; chaining multiple chunks
+// into a MAC over a larger stream is the caller's responsibility (see
+// passcode.BuildManifest for that use case).
+package bmt
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"github.com/snowmerak/passcode"
+)
+
+// leafPrefix and internalPrefix domain-separate leaf and internal node MACs
+// so a segment chosen to equal the concatenation of two node values can't be
+// mistaken for an internal node (the tree-substitution attack behind
+// CVE-2012-2459). This mirrors passcode.ComputeBMT's bmtLeafPrefix /
+// bmtInternalPrefix.
+const (
+	leafPrefix     = 0x00
+	internalPrefix = 0x01
+)
+
+// Tree computes a keyed binary Merkle tree MAC over a chunk of up to
+// chunkSize bytes, split into segmentSize-sized segments. It implements
+// io.Writer so the chunk payload can be written incrementally.
+type Tree struct {
+	p           *passcode.Passcode
+	chunkSize   int
+	segmentSize int
+
+	buf         []byte
+	finalized   bool
+	root        []byte
+	levels      [][][]byte
+	totalLength int
+}
+
+// NewTree creates a Tree that MACs a single chunk of up to chunkSize bytes
+// using p, treating the chunk as segmentSize-sized segments.
+func NewTree(p *passcode.Passcode, chunkSize, segmentSize int) *Tree {
+	return &Tree{
+		p:           p,
+		chunkSize:   chunkSize,
+		segmentSize: segmentSize,
+	}
+}
+
+// Write appends p to the chunk payload. It returns an error once the
+// payload would exceed chunkSize, or once the tree has been finalized by
+// Sum or Proof.
+func (t *Tree) Write(p []byte) (int, error) {
+	if t.finalized {
+		return 0, errors.New("bmt: tree already finalized")
+	}
+	if len(t.buf)+len(p) > t.chunkSize {
+		return 0, fmt.Errorf("bmt: write would exceed chunk size %d", t.chunkSize)
+	}
+
+	t.buf = append(t.buf, p...)
+	return len(p), nil
+}
+
+// Sum finalizes the tree (if not already done) and appends the root MAC to b.
+func (t *Tree) Sum(b []byte) []byte {
+	t.finalize()
+	return append(b, t.root...)
+}
+
+// Proof returns the inclusion path needed to verify, via Verify, that the
+// segment covering offset is part of the root produced by Sum: sibling
+// hashes from the leaf's sibling up to the node just below the root,
+// followed by leftEncode(chunkSize) and leftEncode(totalLength) so a caller
+// outside this package can reconstruct the root's length footer without
+// needing either value (or this package's unexported leaf/internal prefixes)
+// out of band.
+func (t *Tree) Proof(offset int64) ([][]byte, error) {
+	if offset < 0 || offset >= int64(t.chunkSize) {
+		return nil, fmt.Errorf("bmt: offset %d out of range for chunk size %d", offset, t.chunkSize)
+	}
+
+	t.finalize()
+
+	index := int(offset / int64(t.segmentSize))
+	proof := make([][]byte, 0, len(t.levels)+1)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		proof = append(proof, level[index^1])
+		index /= 2
+	}
+	proof = append(proof, leftEncode(uint64(t.chunkSize)), leftEncode(uint64(t.totalLength)))
+	return proof, nil
+}
+
+// Verify reports whether segment is the leaf covering segmentIndex (the
+// segment's position among chunkSize/segmentSize-sized segments) in the tree
+// rooted at root under p, given the inclusion path produced by Tree.Proof.
+// segment must be exactly the segmentSize bytes used to build the tree
+// (zero-padded, as Tree.Write's payload would be for a short trailing
+// segment) — Verify has no way to learn segmentSize itself, so the caller
+// must already know it out of band, the same way it must already know p's
+// algorithm and key.
+func Verify(p *passcode.Passcode, segment []byte, segmentIndex int, proof [][]byte, root []byte) bool {
+	if len(proof) < 2 {
+		return false
+	}
+
+	siblings := proof[:len(proof)-2]
+	chunkSize, _, err := leftDecode(proof[len(proof)-2])
+	if err != nil {
+		return false
+	}
+	totalLength, _, err := leftDecode(proof[len(proof)-1])
+	if err != nil {
+		return false
+	}
+
+	node := p.Compute(leafInput(segment))
+	index := segmentIndex
+	for _, sibling := range siblings {
+		if index%2 == 0 {
+			node = p.Compute(internalInput(node, sibling))
+		} else {
+			node = p.Compute(internalInput(sibling, node))
+		}
+		index /= 2
+	}
+
+	footer := append(leftEncode(chunkSize), leftEncode(totalLength)...)
+	expectedRoot := p.Compute(append(append([]byte{}, node...), footer...))
+	return subtle.ConstantTimeCompare(expectedRoot, root) == 1
+}
+
+func (t *Tree) finalize() {
+	if t.finalized {
+		return
+	}
+
+	totalLength := len(t.buf)
+	numSegments := (t.chunkSize + t.segmentSize - 1) / t.segmentSize
+	width := nextPowerOfTwo(numSegments)
+
+	zeroLeaf := t.p.Compute(leafInput(make([]byte, t.segmentSize)))
+
+	leaves := make([][]byte, width)
+	for i := 0; i < width; i++ {
+		if i >= numSegments {
+			leaves[i] = zeroLeaf
+			continue
+		}
+
+		segment := make([]byte, t.segmentSize)
+		start := i * t.segmentSize
+		if start < totalLength {
+			end := start + t.segmentSize
+			if end > totalLength {
+				end = totalLength
+			}
+			copy(segment, t.buf[start:end])
+		}
+		leaves[i] = t.p.Compute(leafInput(segment))
+	}
+
+	levels := [][][]byte{leaves}
+	current := leaves
+	for len(current) > 1 {
+		next := make([][]byte, len(current)/2)
+		for i := range next {
+			next[i] = t.p.Compute(internalInput(current[2*i], current[2*i+1]))
+		}
+		levels = append(levels, next)
+		current = next
+	}
+
+	footer := append(leftEncode(uint64(t.chunkSize)), leftEncode(uint64(totalLength))...)
+	t.root = t.p.Compute(append(append([]byte{}, current[0]...), footer...))
+	t.levels = levels
+	t.totalLength = totalLength
+	t.finalized = true
+}
+
+// leafInput prepends leafPrefix to segment, so leaf MACs can't collide with
+// internalInput's output regardless of segmentSize.
+func leafInput(segment []byte) []byte {
+	input := make([]byte, 0, 1+len(segment))
+	input = append(input, leafPrefix)
+	input = append(input, segment...)
+	return input
+}
+
+// internalInput prepends internalPrefix to left||right, so internal node
+// MACs can't collide with leafInput's output regardless of segmentSize.
+func internalInput(left, right []byte) []byte {
+	input := make([]byte, 0, 1+len(left)+len(right))
+	input = append(input, internalPrefix)
+	input = append(input, left...)
+	input = append(input, right...)
+	return input
+}
+
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// leftEncode is NIST SP 800-185's left_encode primitive, duplicated here so
+// the tree's length footer doesn't require exporting passcode's internals.
+func leftEncode(x uint64) []byte {
+	if x == 0 {
+		return []byte{1, 0}
+	}
+
+	var temp [8]byte
+	for i := 7; i >= 0; i-- {
+		temp[i] = byte(x & 0xff)
+		x >>= 8
+	}
+
+	start := 0
+	for start < 8 && temp[start] == 0 {
+		start++
+	}
+	n := 8 - start
+
+	result := make([]byte, n+1)
+	result[0] = byte(n)
+	copy(result[1:], temp[start:])
+	return result
+}
+
+// leftDecode is the inverse of leftEncode, duplicated from passcode's
+// internal helper of the same name for the same reason leftEncode is.
+func leftDecode(b []byte) (uint64, int, error) {
+	if len(b) == 0 {
+		return 0, 0, errors.New("bmt: left_decode: empty input")
+	}
+
+	n := int(b[0])
+	if n > 8 || len(b) < n+1 {
+		return 0, 0, fmt.Errorf("bmt: left_decode: invalid length byte %d", n)
+	}
+
+	var x uint64
+	for _, v := range b[1 : 1+n] {
+		x = (x << 8) | uint64(v)
+	}
+	return x, n + 1, nil
+}
@@ -0,0 +1,185 @@
+package bmt_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+	"github.com/snowmerak/passcode/bmt"
+)
+
+func newTestPasscode(t *testing.T) *passcode.Passcode {
+	t.Helper()
+
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	p, err := passcode.NewPasscode(passcode.AlgorithmBLAKE3KeyedMode256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+	return p
+}
+
+func Test_Tree_Sum_Deterministic(t *testing.T) {
+	p := newTestPasscode(t)
+
+	data := []byte("hello binary merkle tree")
+
+	tree1 := bmt.NewTree(p, 64, 8)
+	tree1.Write(data)
+	root1 := tree1.Sum(nil)
+
+	tree2 := bmt.NewTree(p, 64, 8)
+	tree2.Write(data)
+	root2 := tree2.Sum(nil)
+
+	if !bytes.Equal(root1, root2) {
+		t.Fatal("Tree.Sum: same input produced different roots")
+	}
+}
+
+func Test_Tree_Sum_DifferentDataDiffers(t *testing.T) {
+	p := newTestPasscode(t)
+
+	tree1 := bmt.NewTree(p, 64, 8)
+	tree1.Write([]byte("first payload"))
+	root1 := tree1.Sum(nil)
+
+	tree2 := bmt.NewTree(p, 64, 8)
+	tree2.Write([]byte("second payload"))
+	root2 := tree2.Sum(nil)
+
+	if bytes.Equal(root1, root2) {
+		t.Fatal("Tree.Sum: different payloads produced the same root")
+	}
+}
+
+func Test_Tree_Sum_ChunkedWritesMatchSingleWrite(t *testing.T) {
+	p := newTestPasscode(t)
+	data := []byte("a payload written across several Write calls")
+
+	single := bmt.NewTree(p, 64, 8)
+	single.Write(data)
+	expected := single.Sum(nil)
+
+	chunked := bmt.NewTree(p, 64, 8)
+	for i := 0; i < len(data); i += 5 {
+		end := i + 5
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := chunked.Write(data[i:end]); err != nil {
+			t.Fatalf("Write: unexpected error: %v", err)
+		}
+	}
+	got := chunked.Sum(nil)
+
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("Tree.Sum: chunked writes %x do not match single write %x", got, expected)
+	}
+}
+
+func Test_Tree_Write_RejectsOverflow(t *testing.T) {
+	p := newTestPasscode(t)
+
+	tree := bmt.NewTree(p, 16, 8)
+	if _, err := tree.Write(make([]byte, 17)); err == nil {
+		t.Fatal("Write: expected error when exceeding chunk size")
+	}
+}
+
+func Test_Tree_Proof_VerifiesAgainstRoot(t *testing.T) {
+	p := newTestPasscode(t)
+
+	data := []byte("0123456789ABCDEF") // 16 bytes, 2 segments of 8
+	tree := bmt.NewTree(p, 16, 8)
+	tree.Write(data)
+	root := tree.Sum(nil)
+
+	proof, err := tree.Proof(0)
+	if err != nil {
+		t.Fatalf("Proof: unexpected error: %v", err)
+	}
+	if len(proof) < 3 { // 1 sibling + leftEncode(chunkSize) + leftEncode(totalLength)
+		t.Fatalf("Proof: expected at least 3 entries, got %d", len(proof))
+	}
+
+	if !bmt.Verify(p, data[:8], 0, proof, root) {
+		t.Fatal("Verify: expected the real segment to verify against the root")
+	}
+}
+
+func Test_Tree_Verify_RejectsWrongSegment(t *testing.T) {
+	p := newTestPasscode(t)
+
+	data := []byte("0123456789ABCDEF")
+	tree := bmt.NewTree(p, 16, 8)
+	tree.Write(data)
+	root := tree.Sum(nil)
+
+	proof, err := tree.Proof(0)
+	if err != nil {
+		t.Fatalf("Proof: unexpected error: %v", err)
+	}
+
+	if bmt.Verify(p, []byte("WRONGSEG"), 0, proof, root) {
+		t.Fatal("Verify: expected failure for a forged segment")
+	}
+	if bmt.Verify(p, data[8:], 0, proof, root) {
+		t.Fatal("Verify: expected failure when claiming the wrong segment index")
+	}
+}
+
+func Test_Tree_Sum_LeafSegmentDoesNotCollideWithInternalNode(t *testing.T) {
+	p := newTestPasscode(t)
+
+	// Without domain separation, a 64-byte segment equal to two concatenated
+	// 32-byte node values would MAC identically whether it's treated as one
+	// leaf's payload or as an internal node's two children — letting an
+	// attacker substitute a forged leaf for a legitimate subtree
+	// (CVE-2012-2459). A single-segment tree's leaf (segmentSize ==
+	// chunkSize) whose payload is a two-segment tree's two leaf MACs must not
+	// collide with that tree's internal-node MAC over the same two leaves.
+	payload := append(bytes.Repeat([]byte{0xAB}, 32), bytes.Repeat([]byte{0xCD}, 32)...)
+
+	twoSegmentTree := bmt.NewTree(p, 64, 32)
+	twoSegmentTree.Write(payload)
+	internalTreeRoot := twoSegmentTree.Sum(nil)
+
+	// Proof's first entry is the leaf's sibling, so the two proofs yield
+	// leaf 0 and leaf 1 of the two-segment tree.
+	leaf1Proof, err := twoSegmentTree.Proof(0)
+	if err != nil {
+		t.Fatalf("Proof: unexpected error: %v", err)
+	}
+	leaf0Proof, err := twoSegmentTree.Proof(32)
+	if err != nil {
+		t.Fatalf("Proof: unexpected error: %v", err)
+	}
+	nodeValues := append(append([]byte{}, leaf0Proof[0]...), leaf1Proof[0]...)
+
+	tree := bmt.NewTree(p, 64, 64)
+	tree.Write(nodeValues)
+	leafTreeRoot := tree.Sum(nil)
+
+	if bytes.Equal(leafTreeRoot, internalTreeRoot) {
+		t.Fatal("Tree.Sum: leaf-level and internal-level roots collided for the same payload")
+	}
+}
+
+func Test_Tree_Proof_OutOfRange(t *testing.T) {
+	p := newTestPasscode(t)
+
+	tree := bmt.NewTree(p, 16, 8)
+	tree.Write([]byte("short"))
+	tree.Sum(nil)
+
+	if _, err := tree.Proof(-1); err == nil {
+		t.Fatal("Proof: expected error for negative offset")
+	}
+	if _, err := tree.Proof(16); err == nil {
+		t.Fatal("Proof: expected error for offset beyond chunk size")
+	}
+}
@@ -0,0 +1,210 @@
+package passcode
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// bmtSegmentSize is the fixed segment size used by the BMT passcode modes,
+// chosen to match the 256-bit output of the underlying keyed hashers.
+const bmtSegmentSize = 32
+
+const (
+	bmtLeafPrefix     = 0x00
+	bmtInternalPrefix = 0x01
+)
+
+// BMTProofTree is the Merkle tree built by Passcode.ComputeBMT. It retains
+// every level so Proof can produce an inclusion path for any leaf without
+// recomputing the tree.
+type BMTProofTree struct {
+	algo        Algorithm
+	originalLen int
+	levels      [][][]byte // levels[0] is the leaves, the last level is [root]
+}
+
+// ComputeBMT treats challenge as a sequence of bmtSegmentSize-byte segments,
+// zero-pads it up to the next power-of-two segment count, and MACs it as a
+// balanced binary Merkle tree: leaves are H(key, 0x00||segment) and internal
+// nodes are H(key, 0x01||left||right). The returned root is the one-time
+// passcode; tree lets the caller produce inclusion proofs for individual
+// segments via tree.Proof. Only the BMT algorithms support this mode.
+func (p *Passcode) ComputeBMT(challenge []byte) (root []byte, tree *BMTProofTree, err error) {
+	hasher, err := bmtHasherFor(Algorithm(p.algorithm))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	originalLen := len(challenge)
+	numRealSegments := (originalLen + bmtSegmentSize - 1) / bmtSegmentSize
+	if numRealSegments == 0 {
+		numRealSegments = 1
+	}
+	width := nextPowerOfTwoBMT(numRealSegments)
+
+	zeroSegment := make([]byte, bmtSegmentSize)
+	zeroLeaf := bmtLeaf(hasher, p.key, zeroSegment)
+
+	leaves := make([][]byte, width)
+	for i := 0; i < width; i++ {
+		if i >= numRealSegments {
+			leaves[i] = zeroLeaf
+			continue
+		}
+
+		segment := make([]byte, bmtSegmentSize)
+		start := i * bmtSegmentSize
+		end := start + bmtSegmentSize
+		if end > originalLen {
+			end = originalLen
+		}
+		copy(segment, challenge[start:end])
+		leaves[i] = bmtLeaf(hasher, p.key, segment)
+	}
+
+	levels := [][][]byte{leaves}
+	current := leaves
+	for len(current) > 1 {
+		next := make([][]byte, len(current)/2)
+		for i := range next {
+			next[i] = bmtInternalNode(hasher, p.key, current[2*i], current[2*i+1])
+		}
+		levels = append(levels, next)
+		current = next
+	}
+
+	tree = &BMTProofTree{
+		algo:        Algorithm(p.algorithm),
+		originalLen: originalLen,
+		levels:      levels,
+	}
+	return current[0], tree, nil
+}
+
+// Proof returns the sibling path for leafIndex, from the leaf's sibling up
+// to the node just below the root, with the challenge's original (unpadded)
+// length encoded via leftEncode appended as the final element. That length
+// lets VerifyBMTProof tell a real segment from one that only exists because
+// the segment count was padded to a power of two, so the segment passed for
+// a padding-only index is ignored. A short trailing real segment must still
+// be zero-padded to bmtSegmentSize, as ComputeBMT pads it.
+func (t *BMTProofTree) Proof(leafIndex int) [][]byte {
+	width := len(t.levels[0])
+	if leafIndex < 0 || leafIndex >= width {
+		panic(fmt.Sprintf("passcode: leaf index %d out of range for width %d", leafIndex, width))
+	}
+
+	index := leafIndex
+	proof := make([][]byte, 0, len(t.levels))
+	for _, level := range t.levels[:len(t.levels)-1] {
+		proof = append(proof, level[index^1])
+		index /= 2
+	}
+	proof = append(proof, leftEncode(uint64(t.originalLen)))
+	return proof
+}
+
+// VerifyBMTProof checks that segment is the leaf at index in the BMT rooted
+// at root, under algo and key, given the inclusion path produced by
+// BMTProofTree.Proof. segment must be exactly bmtSegmentSize bytes for real
+// (non-padding) leaves; it is ignored for indices past the original
+// challenge's length, since those leaves are always the fixed zero leaf.
+func VerifyBMTProof(algo Algorithm, key, segment []byte, index int, proof [][]byte, root []byte) bool {
+	if len(proof) == 0 {
+		return false
+	}
+
+	hasher, err := bmtHasherFor(algo)
+	if err != nil {
+		return false
+	}
+
+	siblings := proof[:len(proof)-1]
+	originalLen, _, err := leftDecode(proof[len(proof)-1])
+	if err != nil {
+		return false
+	}
+	numRealSegments := (int(originalLen) + bmtSegmentSize - 1) / bmtSegmentSize
+	if numRealSegments == 0 {
+		numRealSegments = 1
+	}
+
+	var node []byte
+	if index < numRealSegments {
+		if len(segment) != bmtSegmentSize {
+			return false
+		}
+		node = bmtLeaf(hasher, key, segment)
+	} else {
+		node = bmtLeaf(hasher, key, make([]byte, bmtSegmentSize))
+	}
+
+	for _, sibling := range siblings {
+		if index%2 == 0 {
+			node = bmtInternalNode(hasher, key, node, sibling)
+		} else {
+			node = bmtInternalNode(hasher, key, sibling, node)
+		}
+		index /= 2
+	}
+
+	return bytes.Equal(node, root)
+}
+
+func bmtHasherFor(algo Algorithm) (Hasher, error) {
+	switch algo {
+	case AlgorithmBLAKE3BMT256:
+		return BLAKE3KeyedMode256, nil
+	case AlgorithmSHA3KMACBMT256:
+		return sha3KMAC256ForPasscode, nil
+	default:
+		return nil, fmt.Errorf("passcode: algorithm %s does not support BMT mode", algo)
+	}
+}
+
+func bmtLeaf(hasher Hasher, key, segment []byte) []byte {
+	input := make([]byte, 0, 1+len(segment))
+	input = append(input, bmtLeafPrefix)
+	input = append(input, segment...)
+	return hasher(key, input)
+}
+
+func bmtInternalNode(hasher Hasher, key, left, right []byte) []byte {
+	input := make([]byte, 0, 1+len(left)+len(right))
+	input = append(input, bmtInternalPrefix)
+	input = append(input, left...)
+	input = append(input, right...)
+	return hasher(key, input)
+}
+
+func nextPowerOfTwoBMT(n int) int {
+	if n <= 1 {
+		return 1
+	}
+
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// leftDecode is the inverse of leftEncode: it reads the length-prefixed
+// encoding and returns the decoded value along with the number of bytes
+// leftEncode(value) would have occupied.
+func leftDecode(b []byte) (uint64, int, error) {
+	if len(b) == 0 {
+		return 0, 0, fmt.Errorf("passcode: left_decode: empty input")
+	}
+
+	n := int(b[0])
+	if n > 8 || len(b) < n+1 {
+		return 0, 0, fmt.Errorf("passcode: left_decode: invalid length byte %d", n)
+	}
+
+	var x uint64
+	for _, v := range b[1 : 1+n] {
+		x = (x << 8) | uint64(v)
+	}
+	return x, n + 1, nil
+}
@@ -0,0 +1,181 @@
+package passcode_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+)
+
+func Test_ComputeBMT_ReturnsRootAndTree(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmBLAKE3BMT256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	challenge := make([]byte, 100) // 4 segments of 32 bytes, last one padded
+	rand.Read(challenge)
+
+	root, tree, err := pc.ComputeBMT(challenge)
+	if err != nil {
+		t.Fatalf("ComputeBMT: unexpected error: %v", err)
+	}
+	if len(root) == 0 {
+		t.Fatal("ComputeBMT: expected non-empty root")
+	}
+	if tree == nil {
+		t.Fatal("ComputeBMT: expected non-nil tree")
+	}
+}
+
+func Test_ComputeBMT_DeterministicAcrossCalls(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmSHA3KMACBMT256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	challenge := []byte("a BMT-mode challenge that isn't segment-aligned")
+
+	root1, _, err := pc.ComputeBMT(challenge)
+	if err != nil {
+		t.Fatalf("ComputeBMT: unexpected error: %v", err)
+	}
+	root2, _, err := pc.ComputeBMT(challenge)
+	if err != nil {
+		t.Fatalf("ComputeBMT: unexpected error: %v", err)
+	}
+	if !bytes.Equal(root1, root2) {
+		t.Fatal("ComputeBMT: expected deterministic root for the same challenge")
+	}
+}
+
+func Test_ComputeBMT_RejectsUnsupportedAlgorithm(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmSHA3KMAC256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	if _, _, err := pc.ComputeBMT([]byte("challenge")); err == nil {
+		t.Fatal("ComputeBMT: expected error for an algorithm without BMT support")
+	}
+}
+
+func Test_BMTProof_VerifiesEachSegment(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmBLAKE3BMT256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	challenge := make([]byte, 130) // 5 real segments, padded to width 8
+	rand.Read(challenge)
+
+	root, tree, err := pc.ComputeBMT(challenge)
+	if err != nil {
+		t.Fatalf("ComputeBMT: unexpected error: %v", err)
+	}
+
+	for i := 0; i < 8; i++ {
+		proof := tree.Proof(i)
+
+		var segment []byte
+		if i*32 < len(challenge) {
+			segment = make([]byte, 32)
+			end := (i + 1) * 32
+			if end > len(challenge) {
+				end = len(challenge)
+			}
+			copy(segment, challenge[i*32:end])
+		} else {
+			segment = make([]byte, 32)
+		}
+
+		if !passcode.VerifyBMTProof(passcode.AlgorithmBLAKE3BMT256, key, segment, i, proof, root) {
+			t.Fatalf("VerifyBMTProof: expected segment %d to verify", i)
+		}
+	}
+}
+
+func Test_BMTProof_RejectsWrongSegment(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmBLAKE3BMT256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	challenge := make([]byte, 64)
+	rand.Read(challenge)
+
+	root, tree, err := pc.ComputeBMT(challenge)
+	if err != nil {
+		t.Fatalf("ComputeBMT: unexpected error: %v", err)
+	}
+
+	proof := tree.Proof(0)
+	wrongSegment := make([]byte, 32)
+	rand.Read(wrongSegment)
+
+	if passcode.VerifyBMTProof(passcode.AlgorithmBLAKE3BMT256, key, wrongSegment, 0, proof, root) {
+		t.Fatal("VerifyBMTProof: expected failure for a forged segment")
+	}
+}
+
+func Test_BMTProof_RejectsWrongIndex(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmBLAKE3BMT256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	challenge := make([]byte, 128)
+	rand.Read(challenge)
+
+	root, tree, err := pc.ComputeBMT(challenge)
+	if err != nil {
+		t.Fatalf("ComputeBMT: unexpected error: %v", err)
+	}
+
+	proof := tree.Proof(1)
+	segment := append([]byte{}, challenge[32:64]...)
+
+	if passcode.VerifyBMTProof(passcode.AlgorithmBLAKE3BMT256, key, segment, 2, proof, root) {
+		t.Fatal("VerifyBMTProof: expected failure when claiming the wrong leaf index")
+	}
+}
+
+func Test_ComputeBMT_DiffersFromFlatCompute(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmBLAKE3BMT256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	challenge := []byte("short challenge")
+	root, _, err := pc.ComputeBMT(challenge)
+	if err != nil {
+		t.Fatalf("ComputeBMT: unexpected error: %v", err)
+	}
+
+	flat := pc.Compute(challenge)
+	if bytes.Equal(root, flat) {
+		t.Fatal("ComputeBMT: expected BMT root to differ from the flat one-shot MAC due to leaf domain separation")
+	}
+}
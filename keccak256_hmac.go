@@ -0,0 +1,16 @@
+package passcode
+
+import (
+	"crypto/hmac"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Keccak256HMAC computes an HMAC over legacy (pre-standardization) Keccak-256,
+// for interop with ecosystems that rely on Keccak rather than NIST-standardized
+// SHA3-256 (e.g. Ethereum addresses and hashes).
+func Keccak256HMAC(key, data []byte) []byte {
+	mac := hmac.New(sha3.NewLegacyKeccak256, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,193 @@
+package passcode
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// manifestOutputLen is the MAC size used for the manifest's overall ID and
+// its per-chunk IDs.
+const manifestOutputLen = 32
+
+// ChunkInfo describes one chunk of a Manifest's input: its MAC, its byte
+// offset within the stream, and its size.
+type ChunkInfo struct {
+	ID     []byte
+	Offset int64
+	Size   int64
+}
+
+// Manifest is a content-addressable description of a stream: an overall MAC
+// (ID) plus a MAC per fixed-size chunk, so a receiver can validate and
+// repair individual chunks without re-hashing the whole object.
+type Manifest struct {
+	ID     []byte
+	Chunks []ChunkInfo
+
+	algo          Algorithm
+	key           []byte
+	customization []byte
+	chunkSize     int64
+}
+
+// BuildManifest walks r, computing a rolling MAC over the whole stream
+// (Manifest.ID) and, simultaneously, a MAC over each chunkSize-sized window
+// (Manifest.Chunks[i]), so chunks MAC independently of one another. For
+// algorithms whose NewStream honors a customization string, each chunk's MAC
+// uses customization = baseCustomization || leftEncode(chunkIndex). BLAKE3
+// keyed mode has no customization-string concept and silently ignores the
+// argument, so for BLAKE3 algorithms the chunk index is instead written as a
+// leftEncode-prefixed binding ahead of the chunk's data; without either,
+// identical chunk content at different offsets would MAC identically.
+func BuildManifest(r io.Reader, algo Algorithm, key, customization []byte, chunkSize int64) (*Manifest, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("passcode: chunkSize must be positive, got %d", chunkSize)
+	}
+
+	switch algo {
+	case AlgorithmSHA3KMAC128, AlgorithmSHA3KMAC256, AlgorithmBLAKE3KeyedMode128, AlgorithmBLAKE3KeyedMode256:
+	default:
+		return nil, fmt.Errorf("passcode: algorithm %s does not support streaming manifests", algo)
+	}
+
+	p, err := NewPasscode(algo, key)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		algo:          algo,
+		key:           key,
+		customization: customization,
+		chunkSize:     chunkSize,
+	}
+
+	overall := p.NewStream(key, customization, manifestOutputLen)
+
+	var chunkHash hash.Hash
+	var chunkOffset, chunkWritten, totalSize int64
+	chunkIndex := 0
+
+	startChunk := func() {
+		chunkHash = newChunkHash(p, key, customization, chunkIndex, manifestOutputLen)
+		chunkOffset = totalSize
+		chunkWritten = 0
+	}
+	startChunk()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := r.Read(buf)
+		data := buf[:n]
+		for len(data) > 0 {
+			remaining := chunkSize - chunkWritten
+			take := int64(len(data))
+			if take > remaining {
+				take = remaining
+			}
+
+			overall.Write(data[:take])
+			chunkHash.Write(data[:take])
+			chunkWritten += take
+			totalSize += take
+			data = data[take:]
+
+			if chunkWritten == chunkSize {
+				m.Chunks = append(m.Chunks, ChunkInfo{ID: chunkHash.Sum(nil), Offset: chunkOffset, Size: chunkWritten})
+				chunkIndex++
+				startChunk()
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	if chunkWritten > 0 {
+		m.Chunks = append(m.Chunks, ChunkInfo{ID: chunkHash.Sum(nil), Offset: chunkOffset, Size: chunkWritten})
+	}
+
+	m.ID = overall.Sum(nil)
+	return m, nil
+}
+
+// Verify recomputes the manifest over r and reports whether its overall ID
+// still matches.
+func (m *Manifest) Verify(r io.Reader) error {
+	recomputed, err := BuildManifest(r, m.algo, m.key, m.customization, m.chunkSize)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(recomputed.ID, m.ID) {
+		return fmt.Errorf("passcode: manifest ID mismatch")
+	}
+	return nil
+}
+
+// VerifyChunk checks data against the MAC recorded for chunk i, without
+// needing any of the manifest's other chunks.
+func (m *Manifest) VerifyChunk(i int, data []byte) error {
+	if i < 0 || i >= len(m.Chunks) {
+		return fmt.Errorf("passcode: chunk index %d out of range", i)
+	}
+
+	chunk := m.Chunks[i]
+	if int64(len(data)) != chunk.Size {
+		return fmt.Errorf("passcode: chunk %d size mismatch: got %d bytes, want %d", i, len(data), chunk.Size)
+	}
+
+	p, err := NewPasscode(m.algo, m.key)
+	if err != nil {
+		return err
+	}
+
+	h := newChunkHash(p, m.key, m.customization, i, manifestOutputLen)
+	h.Write(data)
+	if !bytes.Equal(h.Sum(nil), chunk.ID) {
+		return fmt.Errorf("passcode: chunk %d MAC mismatch", i)
+	}
+	return nil
+}
+
+func chunkCustomization(base []byte, index int) []byte {
+	suffix := leftEncode(uint64(index))
+	result := make([]byte, len(base)+len(suffix))
+	copy(result, base)
+	copy(result[len(base):], suffix)
+	return result
+}
+
+// streamHonorsCustomization reports whether algo's NewStream customization
+// argument actually participates in the MAC. BLAKE3 keyed mode has no
+// customization-string concept, so NewStream silently ignores it.
+func streamHonorsCustomization(algo Algorithm) bool {
+	switch algo {
+	case AlgorithmBLAKE3KeyedMode128, AlgorithmBLAKE3KeyedMode256:
+		return false
+	default:
+		return true
+	}
+}
+
+// newChunkHash returns the stream used to MAC one chunk, binding chunkIndex
+// into it so identical content at different offsets never produces the same
+// chunk MAC. Algorithms that honor a customization string get the index
+// folded into customization (chunkCustomization); algorithms that don't
+// (BLAKE3) instead get a leftEncode-prefixed binding written ahead of the
+// chunk's data.
+func newChunkHash(p *Passcode, key, customization []byte, chunkIndex, outputLen int) hash.Hash {
+	if streamHonorsCustomization(Algorithm(p.algorithm)) {
+		return p.NewStream(key, chunkCustomization(customization, chunkIndex), outputLen)
+	}
+
+	h := p.NewStream(key, customization, outputLen)
+	h.Write(leftEncode(uint64(chunkIndex)))
+	return h
+}
@@ -0,0 +1,143 @@
+package passcode_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+)
+
+func Test_BuildManifest_ChunksCoverWholeStream(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	data := []byte(strings.Repeat("0123456789", 10)) // 100 bytes
+	manifest, err := passcode.BuildManifest(bytes.NewReader(data), passcode.AlgorithmSHA3KMAC256, key, []byte("manifest-test"), 32)
+	if err != nil {
+		t.Fatalf("BuildManifest: unexpected error: %v", err)
+	}
+
+	if len(manifest.Chunks) != 4 {
+		t.Fatalf("BuildManifest: expected 4 chunks, got %d", len(manifest.Chunks))
+	}
+
+	var totalSize int64
+	for i, chunk := range manifest.Chunks {
+		if chunk.Offset != totalSize {
+			t.Fatalf("chunk %d: expected offset %d, got %d", i, totalSize, chunk.Offset)
+		}
+		totalSize += chunk.Size
+	}
+	if totalSize != int64(len(data)) {
+		t.Fatalf("chunks cover %d bytes, want %d", totalSize, len(data))
+	}
+}
+
+func Test_BuildManifest_VerifyRoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	data := make([]byte, 200)
+	rand.Read(data)
+
+	manifest, err := passcode.BuildManifest(bytes.NewReader(data), passcode.AlgorithmBLAKE3KeyedMode256, key, nil, 64)
+	if err != nil {
+		t.Fatalf("BuildManifest: unexpected error: %v", err)
+	}
+
+	if err := manifest.Verify(bytes.NewReader(data)); err != nil {
+		t.Fatalf("Verify: expected success, got error: %v", err)
+	}
+
+	corrupted := append([]byte{}, data...)
+	corrupted[0] ^= 1
+	if err := manifest.Verify(bytes.NewReader(corrupted)); err == nil {
+		t.Fatal("Verify: expected error for corrupted stream")
+	}
+}
+
+func Test_BuildManifest_VerifyChunk(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	data := make([]byte, 130)
+	rand.Read(data)
+
+	manifest, err := passcode.BuildManifest(bytes.NewReader(data), passcode.AlgorithmSHA3KMAC128, key, []byte("chunk-test"), 50)
+	if err != nil {
+		t.Fatalf("BuildManifest: unexpected error: %v", err)
+	}
+
+	for i, chunk := range manifest.Chunks {
+		chunkData := data[chunk.Offset : chunk.Offset+chunk.Size]
+		if err := manifest.VerifyChunk(i, chunkData); err != nil {
+			t.Fatalf("VerifyChunk(%d): expected success, got error: %v", i, err)
+		}
+	}
+
+	if err := manifest.VerifyChunk(0, []byte("wrong data")); err == nil {
+		t.Fatal("VerifyChunk: expected error for mismatched chunk")
+	}
+
+	if err := manifest.VerifyChunk(len(manifest.Chunks), data[:1]); err == nil {
+		t.Fatal("VerifyChunk: expected error for out-of-range index")
+	}
+}
+
+func Test_BuildManifest_BLAKE3ChunksWithIdenticalContentDiffer(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	repeated := bytes.Repeat([]byte{0xAA}, 32)
+	data := append(append([]byte{}, repeated...), repeated...) // two identical 32-byte chunks
+
+	manifest, err := passcode.BuildManifest(bytes.NewReader(data), passcode.AlgorithmBLAKE3KeyedMode256, key, nil, 32)
+	if err != nil {
+		t.Fatalf("BuildManifest: unexpected error: %v", err)
+	}
+	if len(manifest.Chunks) != 2 {
+		t.Fatalf("BuildManifest: expected 2 chunks, got %d", len(manifest.Chunks))
+	}
+
+	// BLAKE3 keyed mode ignores the customization string, so without binding
+	// the chunk index into the hashed data directly, two chunks with the same
+	// bytes at different offsets would MAC identically.
+	if bytes.Equal(manifest.Chunks[0].ID, manifest.Chunks[1].ID) {
+		t.Fatal("BuildManifest: identical chunk content at different offsets produced the same BLAKE3 chunk MAC")
+	}
+
+	if err := manifest.VerifyChunk(0, repeated); err != nil {
+		t.Fatalf("VerifyChunk(0): expected success, got error: %v", err)
+	}
+	if err := manifest.VerifyChunk(1, repeated); err != nil {
+		t.Fatalf("VerifyChunk(1): expected success, got error: %v", err)
+	}
+}
+
+func Test_BuildManifest_RejectsUnstreamableAlgorithm(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	_, err := passcode.BuildManifest(bytes.NewReader([]byte("data")), passcode.AlgorithmSHA3TupleHash256, key, nil, 16)
+	if err == nil {
+		t.Fatal("BuildManifest: expected error for an algorithm without streaming support")
+	}
+}
+
+func Test_BuildManifest_EmptyStream(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	manifest, err := passcode.BuildManifest(bytes.NewReader(nil), passcode.AlgorithmSHA3KMAC256, key, nil, 32)
+	if err != nil {
+		t.Fatalf("BuildManifest: unexpected error: %v", err)
+	}
+	if len(manifest.Chunks) != 0 {
+		t.Fatalf("BuildManifest: expected no chunks for empty stream, got %d", len(manifest.Chunks))
+	}
+	if len(manifest.ID) != 32 {
+		t.Fatalf("BuildManifest: expected 32-byte ID, got %d", len(manifest.ID))
+	}
+}
@@ -0,0 +1,436 @@
+package passcode_test
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/bits"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+)
+
+// This file ports a scaled-down version of the Go runtime's SMHasher-derived
+// quality battery (see runtime/hash_test.go) against passcode.Compute, so
+// every passcode.Algorithm gets real distributional evidence rather than
+// just the average-case avalanche check in avalanche_test.go.
+
+// smhasherAlgorithms lists the algorithms exercised by every test in this
+// file; add a new entry here and it is automatically covered.
+func smhasherAlgorithms() []passcode.Algorithm {
+	return []passcode.Algorithm{
+		passcode.AlgorithmSHA3KMAC128,
+		passcode.AlgorithmSHA3KMAC256,
+		passcode.AlgorithmBLAKE3KeyedMode128,
+		passcode.AlgorithmBLAKE3KeyedMode256,
+		passcode.AlgorithmSHA3TupleHash128,
+		passcode.AlgorithmSHA3TupleHash256,
+		passcode.AlgorithmSHA3ParallelHash128,
+		passcode.AlgorithmSHA3ParallelHash256,
+		passcode.AlgorithmKeccak256HMAC,
+		passcode.AlgorithmSHA3KMACXOF128,
+		passcode.AlgorithmSHA3KMACXOF256,
+		passcode.AlgorithmBLAKE3BMT256,
+		passcode.AlgorithmSHA3KMACBMT256,
+		passcode.AlgorithmArgon2idKeyed,
+	}
+}
+
+func newSMHasherPasscode(t *testing.T, algo passcode.Algorithm, key []byte) *passcode.Passcode {
+	t.Helper()
+	pc, err := passcode.NewPasscode(algo, key)
+	if err != nil {
+		t.Fatalf("NewPasscode(%s): unexpected error: %v", algo, err)
+	}
+	return pc
+}
+
+// expectedBirthdayCollisions estimates the expected number of collisions
+// when hashing `trials` distinct inputs into an outputBits-wide space.
+func expectedBirthdayCollisions(trials int, outputBits int) float64 {
+	n := float64(trials)
+	space := float64(uint64(1) << uint(min(outputBits, 63)))
+	return n * (n - 1) / (2 * space)
+}
+
+// assertCollisionsWithinBirthdayBound fails if outputs collide far more
+// often than the birthday bound predicts. A generous multiplier (plus a
+// flat floor) keeps this from being flaky while still catching a hash that
+// is obviously broken (e.g. truncating its input).
+func assertCollisionsWithinBirthdayBound(t *testing.T, label string, outputs [][]byte, outputBits int) {
+	t.Helper()
+
+	seen := make(map[string]int, len(outputs))
+	collisions := 0
+	for _, out := range outputs {
+		seen[string(out)]++
+	}
+	for _, count := range seen {
+		if count > 1 {
+			collisions += count - 1
+		}
+	}
+
+	expected := expectedBirthdayCollisions(len(outputs), outputBits)
+	bound := expected*4 + 2
+	if float64(collisions) > bound {
+		t.Errorf("%s: observed %d collisions across %d outputs, expected ~%.4f (bound %.4f)",
+			label, collisions, len(outputs), expected, bound)
+	}
+}
+
+// Test_SMHasher_Sanity checks that Compute depends only on key/challenge
+// contents, not on where those bytes happen to sit in memory.
+func Test_SMHasher_Sanity(t *testing.T) {
+	for _, algo := range smhasherAlgorithms() {
+		algo := algo
+		t.Run(string(algo), func(t *testing.T) {
+			key := make([]byte, 32)
+			rand.Read(key)
+			challenge := make([]byte, 24)
+			rand.Read(challenge)
+
+			pc := newSMHasherPasscode(t, algo, key)
+			baseline := pc.Compute(challenge)
+
+			// Re-extract the same key/challenge bytes from random padded
+			// buffers at varying offsets and alignments.
+			for trial := 0; trial < 8; trial++ {
+				padBefore := trial
+				padAfter := 7 - trial
+
+				paddedKey := make([]byte, padBefore+len(key)+padAfter)
+				rand.Read(paddedKey)
+				copy(paddedKey[padBefore:], key)
+				extractedKey := paddedKey[padBefore : padBefore+len(key)]
+
+				paddedChallenge := make([]byte, padAfter+len(challenge)+padBefore)
+				rand.Read(paddedChallenge)
+				copy(paddedChallenge[padAfter:], challenge)
+				extractedChallenge := paddedChallenge[padAfter : padAfter+len(challenge)]
+
+				altPC := newSMHasherPasscode(t, algo, extractedKey)
+				got := altPC.Compute(extractedChallenge)
+				if string(got) != string(baseline) {
+					t.Fatalf("%s: output changed for identical key/challenge at alignment %d", algo, trial)
+				}
+			}
+		})
+	}
+}
+
+// Test_SMHasher_SparseKeys hashes every challenge with up to k bits set
+// (across a handful of lengths) and checks the collision rate against the
+// birthday bound. A hash with poor bit mixing tends to collide sparse,
+// low-weight inputs far more often than chance predicts.
+func Test_SMHasher_SparseKeys(t *testing.T) {
+	const k = 6
+	lengths := []int{2, 3}
+	if testing.Short() {
+		lengths = []int{2}
+	}
+
+	for _, algo := range smhasherAlgorithms() {
+		algo := algo
+		t.Run(string(algo), func(t *testing.T) {
+			key := make([]byte, 32)
+			rand.Read(key)
+			pc := newSMHasherPasscode(t, algo, key)
+
+			var outputs [][]byte
+			for _, length := range lengths {
+				bitLen := length * 8
+				for _, setBits := range sparseBitPositions(bitLen, k) {
+					challenge := make([]byte, length)
+					for _, b := range setBits {
+						challenge[b/8] |= 1 << uint(b%8)
+					}
+					outputs = append(outputs, pc.Compute(challenge))
+				}
+			}
+
+			assertCollisionsWithinBirthdayBound(t, string(algo), outputs, 256)
+		})
+	}
+}
+
+// sparseBitPositions enumerates every combination of up to k set bit
+// positions out of bitLen, via the standard "enumerate popcount <= k"
+// integer walk. bitLen is kept small by the caller so this stays fast.
+func sparseBitPositions(bitLen, k int) [][]int {
+	var combos [][]int
+	limit := uint64(1) << uint(bitLen)
+	for v := uint64(0); v < limit; v++ {
+		if bits.OnesCount64(v) > k {
+			continue
+		}
+		var positions []int
+		for b := 0; b < bitLen; b++ {
+			if v&(1<<uint(b)) != 0 {
+				positions = append(positions, b)
+			}
+		}
+		combos = append(combos, positions)
+	}
+	return combos
+}
+
+// Test_SMHasher_Permutation hashes every permutation of a small dictionary
+// of 32-bit words and checks for excess collisions — a hash that is not
+// sensitive to element order would fail this immediately.
+func Test_SMHasher_Permutation(t *testing.T) {
+	dictionary := []uint32{0x00000000, 0x00000001, 0x80000000, 0xffffffff}
+
+	for _, algo := range smhasherAlgorithms() {
+		algo := algo
+		t.Run(string(algo), func(t *testing.T) {
+			key := make([]byte, 32)
+			rand.Read(key)
+			pc := newSMHasherPasscode(t, algo, key)
+
+			var outputs [][]byte
+			permuteWords(dictionary, func(perm []uint32) {
+				challenge := make([]byte, 4*len(perm))
+				for i, w := range perm {
+					binary.LittleEndian.PutUint32(challenge[i*4:], w)
+				}
+				outputs = append(outputs, pc.Compute(challenge))
+			})
+
+			assertCollisionsWithinBirthdayBound(t, string(algo), outputs, 256)
+		})
+	}
+}
+
+// permuteWords calls f once per permutation of words (Heap's algorithm).
+func permuteWords(words []uint32, f func([]uint32)) {
+	perm := append([]uint32{}, words...)
+	var generate func(k int)
+	generate = func(k int) {
+		if k == 1 {
+			f(append([]uint32{}, perm...))
+			return
+		}
+		for i := 0; i < k; i++ {
+			generate(k - 1)
+			if k%2 == 0 {
+				perm[i], perm[k-1] = perm[k-1], perm[i]
+			} else {
+				perm[0], perm[k-1] = perm[k-1], perm[0]
+			}
+		}
+	}
+	generate(len(perm))
+}
+
+// Test_SMHasher_WindowedKeys slides a W-bit window across a 128-bit
+// challenge, enumerating every value in that window (with everything
+// outside it zeroed) and checking collisions per window position. This
+// catches hashes that ignore or barely mix certain bit ranges of the input.
+func Test_SMHasher_WindowedKeys(t *testing.T) {
+	const challengeBits = 128
+	const windowBits = 10
+
+	for _, algo := range smhasherAlgorithms() {
+		algo := algo
+		t.Run(string(algo), func(t *testing.T) {
+			key := make([]byte, 32)
+			rand.Read(key)
+			pc := newSMHasherPasscode(t, algo, key)
+
+			for start := 0; start <= challengeBits-windowBits; start += windowBits {
+				var outputs [][]byte
+				for v := uint64(0); v < (1 << windowBits); v++ {
+					challenge := make([]byte, challengeBits/8)
+					for b := 0; b < windowBits; b++ {
+						if v&(1<<uint(b)) != 0 {
+							bitPos := start + b
+							challenge[bitPos/8] |= 1 << uint(bitPos%8)
+						}
+					}
+					outputs = append(outputs, pc.Compute(challenge))
+				}
+				assertCollisionsWithinBirthdayBound(t, string(algo), outputs, 256)
+			}
+		})
+	}
+}
+
+// Test_SMHasher_AvalancheBitMatrix builds a per-output-bit x per-input-bit
+// flip-count matrix over N random trials and checks each cell against a
+// +/-4 standard-deviation band around N/2, catching local bias that an
+// averaged ratio (see avalanche_test.go) can hide. It fails only when more
+// than 1/20 of the cells fall outside the band; individual cells outside it
+// do not fail the test.
+func Test_SMHasher_AvalancheBitMatrix(t *testing.T) {
+	trials := 256
+	if testing.Short() {
+		trials = 64
+	}
+
+	for _, algo := range smhasherAlgorithms() {
+		algo := algo
+		t.Run(string(algo), func(t *testing.T) {
+			key := make([]byte, 32)
+			rand.Read(key)
+			pc := newSMHasherPasscode(t, algo, key)
+
+			challenge := make([]byte, 32)
+			rand.Read(challenge)
+			baseline := pc.Compute(challenge)
+			inputBits := len(challenge) * 8
+			outputBits := len(baseline) * 8
+
+			flips := make([][]int, inputBits)
+			for i := range flips {
+				flips[i] = make([]int, outputBits)
+			}
+
+			for trial := 0; trial < trials; trial++ {
+				msg := make([]byte, len(challenge))
+				rand.Read(msg)
+				base := pc.Compute(msg)
+
+				for ib := 0; ib < inputBits; ib++ {
+					flipped := make([]byte, len(msg))
+					copy(flipped, msg)
+					flipped[ib/8] ^= 1 << uint(ib%8)
+					out := pc.Compute(flipped)
+
+					for ob := 0; ob < outputBits; ob++ {
+						if (base[ob/8]^out[ob/8])&(1<<uint(ob%8)) != 0 {
+							flips[ib][ob]++
+						}
+					}
+				}
+			}
+
+			// A fair avalanche should flip each output bit ~N/2 times per
+			// input bit. Use a loose +/-4 standard-deviation band (binomial,
+			// p=0.5) rather than a hard 50% requirement, since any single
+			// cell is a low-sample-size statistic.
+			expected := float64(trials) / 2
+			low := expected - 4*sqrtApprox(float64(trials))/2
+			high := expected + 4*sqrtApprox(float64(trials))/2
+
+			badCells := 0
+			for ib := 0; ib < inputBits; ib++ {
+				for ob := 0; ob < outputBits; ob++ {
+					count := float64(flips[ib][ob])
+					if count < low || count > high {
+						badCells++
+					}
+				}
+			}
+
+			// A handful of outlier cells is expected by chance across a
+			// large matrix; only fail if a meaningful fraction drift out of
+			// band, which signals systematic bias rather than noise.
+			totalCells := inputBits * outputBits
+			if badCells > totalCells/20 {
+				t.Errorf("%s: %d/%d avalanche matrix cells outside expected band [%.1f, %.1f] (trials=%d)",
+					algo, badCells, totalCells, low, high, trials)
+			}
+		})
+	}
+}
+
+func sqrtApprox(x float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	guess := x
+	for i := 0; i < 40; i++ {
+		guess = 0.5 * (guess + x/guess)
+	}
+	return guess
+}
+
+// Test_SMHasher_TwoNonZero hashes every challenge with exactly two non-zero
+// bytes (at every pair of positions, each drawn from a small fixed set of
+// non-zero byte values) and checks for excess collisions.
+func Test_SMHasher_TwoNonZero(t *testing.T) {
+	length := 8
+	values := []byte{0x01, 0x80, 0xff}
+	if testing.Short() {
+		length = 4
+		values = []byte{0x01, 0xff}
+	}
+
+	for _, algo := range smhasherAlgorithms() {
+		algo := algo
+		t.Run(string(algo), func(t *testing.T) {
+			key := make([]byte, 32)
+			rand.Read(key)
+			pc := newSMHasherPasscode(t, algo, key)
+
+			var outputs [][]byte
+			for i := 0; i < length; i++ {
+				for j := i + 1; j < length; j++ {
+					for _, vi := range values {
+						for _, vj := range values {
+							challenge := make([]byte, length)
+							challenge[i] = vi
+							challenge[j] = vj
+							outputs = append(outputs, pc.Compute(challenge))
+						}
+					}
+				}
+			}
+
+			assertCollisionsWithinBirthdayBound(t, string(algo), outputs, 256)
+		})
+	}
+}
+
+// Test_SMHasher_Cyclic hashes byte-rotations of a fixed pattern repeated to
+// fill a buffer, which tends to expose hashes with short effective cycles.
+func Test_SMHasher_Cyclic(t *testing.T) {
+	pattern := []byte{0x5a, 0xa5, 0x3c, 0xc3}
+	length := 32
+
+	for _, algo := range smhasherAlgorithms() {
+		algo := algo
+		t.Run(string(algo), func(t *testing.T) {
+			key := make([]byte, 32)
+			rand.Read(key)
+			pc := newSMHasherPasscode(t, algo, key)
+
+			var outputs [][]byte
+			for shift := 0; shift < len(pattern); shift++ {
+				challenge := make([]byte, length)
+				for i := range challenge {
+					challenge[i] = pattern[(i+shift)%len(pattern)]
+				}
+				outputs = append(outputs, pc.Compute(challenge))
+			}
+
+			assertCollisionsWithinBirthdayBound(t, string(algo), outputs, 256)
+		})
+	}
+}
+
+// Test_SMHasher_Seed checks that the same challenge hashed under distinct
+// random keys ("seeds") produces distinct outputs with no excess collisions.
+func Test_SMHasher_Seed(t *testing.T) {
+	seeds := 64
+	if testing.Short() {
+		seeds = 16
+	}
+
+	challenge := []byte("fixed challenge for seed sweep")
+
+	for _, algo := range smhasherAlgorithms() {
+		algo := algo
+		t.Run(string(algo), func(t *testing.T) {
+			var outputs [][]byte
+			for i := 0; i < seeds; i++ {
+				key := make([]byte, 32)
+				rand.Read(key)
+				pc := newSMHasherPasscode(t, algo, key)
+				outputs = append(outputs, pc.Compute(challenge))
+			}
+
+			assertCollisionsWithinBirthdayBound(t, string(algo), outputs, 256)
+		})
+	}
+}
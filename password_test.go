@@ -0,0 +1,112 @@
+package passcode_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+	"golang.org/x/crypto/argon2"
+)
+
+// Test_Argon2id_MatchesUpstreamKnownAnswerTest checks golang.org/x/crypto's
+// IDKey against one of that package's own published known-answer vectors
+// (argon2_test.go's testVectors, time=2/memory=64KiB/threads=1/keyLen=24).
+// It calls IDKey directly, so it does not exercise the argument order that
+// NewPasscodeFromPassword uses. This is NOT the RFC 9106 conformance
+// vector: that vector requires a non-empty secret and associated data, and
+// IDKey's signature has no parameters for either, so it can't be reproduced
+// through the API NewPasscodeFromPassword is built on. The expected hash
+// below is copied from x/crypto's source, not generated by this repo, so it
+// still gives independent assurance despite the narrower scope.
+func Test_Argon2id_MatchesUpstreamKnownAnswerTest(t *testing.T) {
+	password := []byte("password")
+	salt := []byte("somesalt")
+	want, err := hex.DecodeString("068d62b26455936aa6ebe60060b0a65870dbfa3ddf8d41f7")
+	if err != nil {
+		t.Fatalf("hex.DecodeString: unexpected error: %v", err)
+	}
+
+	got := argon2.IDKey(password, salt, 2, 64, 1, uint32(len(want)))
+	if !bytes.Equal(got, want) {
+		t.Fatalf("argon2.IDKey: got %x, want %x", got, want)
+	}
+}
+
+func Test_NewPasscodeFromPassword_Deterministic(t *testing.T) {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	password := []byte("correct horse battery staple")
+
+	pc1, err := passcode.NewPasscodeFromPassword(passcode.AlgorithmArgon2idKeyed, password, salt, 2)
+	if err != nil {
+		t.Fatalf("NewPasscodeFromPassword: unexpected error: %v", err)
+	}
+	pc2, err := passcode.NewPasscodeFromPassword(passcode.AlgorithmArgon2idKeyed, password, salt, 2)
+	if err != nil {
+		t.Fatalf("NewPasscodeFromPassword: unexpected error: %v", err)
+	}
+
+	challenge := []byte("challenge data")
+	if !bytes.Equal(pc1.Compute(challenge), pc2.Compute(challenge)) {
+		t.Fatal("NewPasscodeFromPassword: expected the same password/salt/cost to derive the same key")
+	}
+}
+
+func Test_NewPasscodeFromPassword_DifferentSaltsDiffer(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	salt1 := make([]byte, 16)
+	salt2 := make([]byte, 16)
+	rand.Read(salt1)
+	rand.Read(salt2)
+
+	pc1, err := passcode.NewPasscodeFromPassword(passcode.AlgorithmArgon2idKeyed, password, salt1, 2)
+	if err != nil {
+		t.Fatalf("NewPasscodeFromPassword: unexpected error: %v", err)
+	}
+	pc2, err := passcode.NewPasscodeFromPassword(passcode.AlgorithmArgon2idKeyed, password, salt2, 2)
+	if err != nil {
+		t.Fatalf("NewPasscodeFromPassword: unexpected error: %v", err)
+	}
+
+	challenge := []byte("challenge data")
+	if bytes.Equal(pc1.Compute(challenge), pc2.Compute(challenge)) {
+		t.Fatal("NewPasscodeFromPassword: expected different salts to derive different keys")
+	}
+}
+
+func Test_NewPasscodeFromPassword_RejectsNonPositiveCost(t *testing.T) {
+	if _, err := passcode.NewPasscodeFromPassword(passcode.AlgorithmArgon2idKeyed, []byte("pw"), []byte("salt"), 0); err == nil {
+		t.Fatal("NewPasscodeFromPassword: expected error for non-positive cost")
+	}
+}
+
+func Test_NewPasscodeFromPassword_RejectsUnsupportedAlgorithm(t *testing.T) {
+	if _, err := passcode.NewPasscodeFromPassword(passcode.AlgorithmSHA3KMAC256, []byte("pw"), []byte("salt"), 2); err == nil {
+		t.Fatal("NewPasscodeFromPassword: expected error for an algorithm without password support")
+	}
+}
+
+func Test_Passcode_Verify(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmBLAKE3KeyedMode256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	challenge := []byte("a challenge")
+	otp := pc.Compute(challenge)
+
+	if !pc.Verify(challenge, otp) {
+		t.Fatal("Verify: expected the real OTP to verify")
+	}
+
+	forged := append([]byte{}, otp...)
+	forged[0] ^= 1
+	if pc.Verify(challenge, forged) {
+		t.Fatal("Verify: expected a forged OTP to fail")
+	}
+}
@@ -102,3 +102,11 @@ func kmac(key, customization, data []byte, outputLen int, rate int, newCShake fu
 	hasher.Read(output)
 	return output
 }
+
+func sha3KMAC128ForPasscode(key, data []byte) []byte {
+	return SHA3KMAC128(key, nil, data, 32)
+}
+
+func sha3KMAC256ForPasscode(key, data []byte) []byte {
+	return SHA3KMAC256(key, nil, data, 32)
+}
@@ -0,0 +1,82 @@
+package passcode
+
+import (
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// XOFHash is a hash.Hash that can additionally be squeezed for extra output
+// bytes beyond its fixed Size(), similar to a SHAKE/cSHAKE XOF.
+type XOFHash interface {
+	hash.Hash
+	// XOF returns a reader that squeezes additional output from the current
+	// hash state without disturbing it, so Write/Sum keep working as usual.
+	XOF() io.Reader
+}
+
+type kmacHash struct {
+	state         sha3.ShakeHash
+	rate          int
+	size          int
+	key           []byte
+	customization []byte
+	newCShake     func([]byte, []byte) sha3.ShakeHash
+}
+
+func newKMACHash(key, customization []byte, outputLen, rate int, newCShake func([]byte, []byte) sha3.ShakeHash) *kmacHash {
+	h := &kmacHash{
+		rate:          rate,
+		size:          outputLen,
+		key:           key,
+		customization: customization,
+		newCShake:     newCShake,
+	}
+	h.Reset()
+	return h
+}
+
+// NewKMAC128 returns a streaming KMAC128 hash.Hash (also an XOFHash), so
+// large messages can be written in chunks instead of materialized up front.
+// The key and customization are pre-absorbed; outputLen only takes effect on
+// Sum, as the suffix appended there and the number of bytes Sum returns.
+func NewKMAC128(key, customization []byte, outputLen int) hash.Hash {
+	return newKMACHash(key, customization, outputLen, 168, sha3.NewCShake128)
+}
+
+// NewKMAC256 returns a streaming KMAC256 hash.Hash (also an XOFHash).
+func NewKMAC256(key, customization []byte, outputLen int) hash.Hash {
+	return newKMACHash(key, customization, outputLen, 136, sha3.NewCShake256)
+}
+
+func (h *kmacHash) Write(p []byte) (int, error) {
+	return h.state.Write(p)
+}
+
+func (h *kmacHash) Sum(b []byte) []byte {
+	clone := h.state.Clone()
+	clone.Write(rightEncode(uint64(h.size * 8)))
+
+	output := make([]byte, h.size)
+	clone.Read(output)
+	return append(b, output...)
+}
+
+func (h *kmacHash) Reset() {
+	h.state = h.newCShake([]byte("KMAC"), h.customization)
+	h.state.Write(bytepad(encodeString(h.key), h.rate))
+}
+
+func (h *kmacHash) Size() int { return h.size }
+
+func (h *kmacHash) BlockSize() int { return h.rate }
+
+// XOF squeezes additional output from the current message under KMACXOF's
+// domain separation (a rightEncode(0) suffix instead of rightEncode(outputLen*8)),
+// letting a caller read as many extra bytes as it wants on demand.
+func (h *kmacHash) XOF() io.Reader {
+	clone := h.state.Clone()
+	clone.Write(rightEncode(0))
+	return clone
+}
@@ -0,0 +1,40 @@
+package passcode
+
+import "golang.org/x/crypto/sha3"
+
+// SHA3KMACXOF128 computes KMAC128's extendable-output mode (NIST SP 800-185):
+// identical to SHA3KMAC128 except the final suffix is rightEncode(0) rather
+// than rightEncode(outputLen*8). This is the distinction NIST draws between
+// KMAC and KMACXOF, and it means outputLen may be changed per call without
+// collision risk, unlike plain KMAC.
+func SHA3KMACXOF128(key, customization, data []byte, outputLen int) []byte {
+	return kmacXOF(key, customization, data, outputLen, 168, sha3.NewCShake128)
+}
+
+// SHA3KMACXOF256 computes KMAC256's extendable-output mode.
+func SHA3KMACXOF256(key, customization, data []byte, outputLen int) []byte {
+	return kmacXOF(key, customization, data, outputLen, 136, sha3.NewCShake256)
+}
+
+func kmacXOF(key, customization, data []byte, outputLen int, rate int, newCShake func([]byte, []byte) sha3.ShakeHash) []byte {
+	encodedKey := encodeString(key)
+	paddedKey := bytepad(encodedKey, rate)
+
+	hasher := newCShake([]byte("KMAC"), customization)
+
+	hasher.Write(paddedKey)
+	hasher.Write(data)
+	hasher.Write(rightEncode(0))
+
+	output := make([]byte, outputLen)
+	hasher.Read(output)
+	return output
+}
+
+func sha3KMACXOF128ForPasscode(key, data []byte) []byte {
+	return SHA3KMACXOF128(key, nil, data, 32)
+}
+
+func sha3KMACXOF256ForPasscode(key, data []byte) []byte {
+	return SHA3KMACXOF256(key, nil, data, 32)
+}
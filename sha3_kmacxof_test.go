@@ -0,0 +1,102 @@
+package passcode_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+)
+
+func Test_SHA3KMACXOF128_Consistent(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("xof-consistency")
+	data := []byte("xof test data")
+
+	result1 := passcode.SHA3KMACXOF128(key, customization, data, 32)
+	result2 := passcode.SHA3KMACXOF128(key, customization, data, 32)
+
+	if !bytes.Equal(result1, result2) {
+		t.Fatal("SHA3KMACXOF128: inconsistent results")
+	}
+}
+
+func Test_SHA3KMACXOF256_LongerOutputExtendsShorter(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("xof-extend")
+	data := []byte("xof test data")
+
+	short := passcode.SHA3KMACXOF256(key, customization, data, 16)
+	long := passcode.SHA3KMACXOF256(key, customization, data, 32)
+
+	if !bytes.Equal(short, long[:16]) {
+		t.Fatal("SHA3KMACXOF256: changing outputLen is not a pure extension of the squeeze")
+	}
+}
+
+func Test_SHA3KMACXOF_DiffersFromSHA3KMAC(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("xof-vs-kmac")
+	data := []byte("same input")
+
+	kmac := passcode.SHA3KMAC256(key, customization, data, 32)
+	kmacXOF := passcode.SHA3KMACXOF256(key, customization, data, 32)
+
+	if bytes.Equal(kmac, kmacXOF) {
+		t.Fatal("SHA3KMACXOF256: expected different output from SHA3KMAC256 due to differing suffix")
+	}
+}
+
+func Test_Keccak256HMAC_Consistent(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	data := []byte("keccak hmac test data")
+
+	result1 := passcode.Keccak256HMAC(key, data)
+	result2 := passcode.Keccak256HMAC(key, data)
+
+	if !bytes.Equal(result1, result2) {
+		t.Fatal("Keccak256HMAC: inconsistent results")
+	}
+	if len(result1) != 32 {
+		t.Fatalf("Keccak256HMAC: expected 32 bytes, got %d", len(result1))
+	}
+}
+
+func Test_Keccak256HMAC_DifferentKeys(t *testing.T) {
+	key1 := make([]byte, 32)
+	key2 := make([]byte, 32)
+	rand.Read(key1)
+	rand.Read(key2)
+	data := []byte("same data")
+
+	result1 := passcode.Keccak256HMAC(key1, data)
+	result2 := passcode.Keccak256HMAC(key2, data)
+
+	if bytes.Equal(result1, result2) {
+		t.Fatal("Keccak256HMAC: different keys produced the same MAC")
+	}
+}
+
+func Test_Passcode_KeccakAndKMACXOFAlgorithms(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	challenge := []byte("challenge data")
+
+	for _, algo := range []passcode.Algorithm{
+		passcode.AlgorithmKeccak256HMAC,
+		passcode.AlgorithmSHA3KMACXOF128,
+		passcode.AlgorithmSHA3KMACXOF256,
+	} {
+		pc, err := passcode.NewPasscode(algo, key)
+		if err != nil {
+			t.Fatalf("NewPasscode(%s): unexpected error: %v", algo, err)
+		}
+		if otp := pc.Compute(challenge); len(otp) == 0 {
+			t.Fatalf("Compute(%s): expected non-empty output", algo)
+		}
+	}
+}
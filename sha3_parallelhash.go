@@ -0,0 +1,91 @@
+package passcode
+
+import (
+	"runtime"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// defaultParallelHashBlockSize is used when wiring ParallelHash into the
+// Passcode factory, where the single-shot Hasher signature has no room for
+// a caller-supplied block size.
+const defaultParallelHashBlockSize = 8192
+
+// SHA3ParallelHash128 computes a keyed ParallelHash128 (NIST SP 800-185) over
+// data, split into blockSize-sized blocks and hashed with inner parallelism.
+// The outer cSHAKE first absorbs bytepad(encode_string(key), rate), as KMAC
+// does, so the output is not the unkeyed NIST ParallelHash128 digest.
+func SHA3ParallelHash128(key, customization, data []byte, blockSize, outputLen int) []byte {
+	return parallelHash(key, customization, data, blockSize, outputLen, 32, 168, sha3.NewCShake128)
+}
+
+// SHA3ParallelHash256 computes a keyed ParallelHash256 (NIST SP 800-185) over
+// data, keyed the same way as SHA3ParallelHash128.
+func SHA3ParallelHash256(key, customization, data []byte, blockSize, outputLen int) []byte {
+	return parallelHash(key, customization, data, blockSize, outputLen, 64, 136, sha3.NewCShake256)
+}
+
+func parallelHash(key, customization, data []byte, blockSize, outputLen, chainLen, rate int, newCShake func([]byte, []byte) sha3.ShakeHash) []byte {
+	encodedKey := encodeString(key)
+	paddedKey := bytepad(encodedKey, rate)
+
+	blockCount := (len(data) + blockSize - 1) / blockSize
+	if blockCount == 0 {
+		blockCount = 1
+	}
+	chains := make([][]byte, blockCount)
+
+	jobs := make(chan int, blockCount)
+	workers := runtime.NumCPU()
+	if workers > blockCount {
+		workers = blockCount
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				start := idx * blockSize
+				end := start + blockSize
+				if end > len(data) {
+					end = len(data)
+				}
+
+				inner := newCShake(nil, nil)
+				inner.Write(data[start:end])
+				chain := make([]byte, chainLen)
+				inner.Read(chain)
+				chains[idx] = chain
+			}
+		}()
+	}
+	for idx := 0; idx < blockCount; idx++ {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	hasher := newCShake([]byte("ParallelHash"), customization)
+	hasher.Write(paddedKey)
+	hasher.Write(leftEncode(uint64(blockSize)))
+	for _, chain := range chains {
+		hasher.Write(chain)
+	}
+	hasher.Write(rightEncode(uint64(blockCount)))
+	hasher.Write(rightEncode(uint64(outputLen * 8)))
+
+	output := make([]byte, outputLen)
+	hasher.Read(output)
+	return output
+}
+
+func sha3ParallelHash128ForPasscode(key, data []byte) []byte {
+	return SHA3ParallelHash128(key, nil, data, defaultParallelHashBlockSize, 32)
+}
+
+func sha3ParallelHash256ForPasscode(key, data []byte) []byte {
+	return SHA3ParallelHash256(key, nil, data, defaultParallelHashBlockSize, 64)
+}
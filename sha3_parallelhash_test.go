@@ -0,0 +1,56 @@
+package passcode_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+)
+
+func Test_SHA3ParallelHash256_Consistent(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("parallelhash-consistency")
+	data := make([]byte, 20000) // spans several blocks at the default block size
+	rand.Read(data)
+
+	result1 := passcode.SHA3ParallelHash256(key, customization, data, 8192, 32)
+	result2 := passcode.SHA3ParallelHash256(key, customization, data, 8192, 32)
+
+	if !bytes.Equal(result1, result2) {
+		t.Fatal("SHA3ParallelHash256: inconsistent results")
+	}
+}
+
+func Test_SHA3ParallelHash128_DifferentBlockSizesDiffer(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	data := make([]byte, 20000)
+	rand.Read(data)
+
+	small := passcode.SHA3ParallelHash128(key, nil, data, 4096, 32)
+	large := passcode.SHA3ParallelHash128(key, nil, data, 8192, 32)
+
+	if bytes.Equal(small, large) {
+		t.Fatal("SHA3ParallelHash128: expected different block sizes to produce different digests")
+	}
+}
+
+func Test_Passcode_ParallelHashAlgorithms(t *testing.T) {
+	key := make([]byte, 32)
+	challenge := []byte("challenge data")
+
+	for _, algo := range []passcode.Algorithm{
+		passcode.AlgorithmSHA3ParallelHash128,
+		passcode.AlgorithmSHA3ParallelHash256,
+	} {
+		pc, err := passcode.NewPasscode(algo, key)
+		if err != nil {
+			t.Fatalf("NewPasscode(%s): unexpected error: %v", algo, err)
+		}
+		if otp := pc.Compute(challenge); len(otp) == 0 {
+			t.Fatalf("Compute(%s): expected non-empty output", algo)
+		}
+	}
+}
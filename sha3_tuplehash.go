@@ -0,0 +1,37 @@
+package passcode
+
+import "golang.org/x/crypto/sha3"
+
+// SHA3TupleHash128 computes TupleHash128 (NIST SP 800-185) over the given
+// tuple of elements. Each element is individually length-encoded before
+// being absorbed, so ("ab", "c") and ("a", "bc") never collide.
+func SHA3TupleHash128(keys [][]byte, customization []byte, outputLen int) []byte {
+	return tupleHash(keys, customization, outputLen, sha3.NewCShake128)
+}
+
+// SHA3TupleHash256 computes TupleHash256 (NIST SP 800-185) over the given
+// tuple of elements.
+func SHA3TupleHash256(keys [][]byte, customization []byte, outputLen int) []byte {
+	return tupleHash(keys, customization, outputLen, sha3.NewCShake256)
+}
+
+func tupleHash(elements [][]byte, customization []byte, outputLen int, newCShake func([]byte, []byte) sha3.ShakeHash) []byte {
+	hasher := newCShake([]byte("TupleHash"), customization)
+
+	for _, element := range elements {
+		hasher.Write(encodeString(element))
+	}
+	hasher.Write(rightEncode(uint64(outputLen * 8)))
+
+	output := make([]byte, outputLen)
+	hasher.Read(output)
+	return output
+}
+
+func sha3TupleHash128ForPasscode(key, data []byte) []byte {
+	return SHA3TupleHash128([][]byte{key, data}, nil, 32)
+}
+
+func sha3TupleHash256ForPasscode(key, data []byte) []byte {
+	return SHA3TupleHash256([][]byte{key, data}, nil, 32)
+}
@@ -0,0 +1,60 @@
+package passcode_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+)
+
+func Test_SHA3TupleHash256_Consistent(t *testing.T) {
+	elements := [][]byte{[]byte("ab"), []byte("c")}
+	customization := []byte("tuplehash-consistency")
+
+	result1 := passcode.SHA3TupleHash256(elements, customization, 32)
+	result2 := passcode.SHA3TupleHash256(elements, customization, 32)
+
+	if !bytes.Equal(result1, result2) {
+		t.Fatal("SHA3TupleHash256: inconsistent results")
+	}
+}
+
+// Test_SHA3TupleHash256_ElementsDoNotBleedTogether confirms the property the
+// request calls out by name: each element is length-encoded before being
+// absorbed (via encodeString), so splitting the same concatenated bytes
+// across tuple elements differently changes the digest.
+func Test_SHA3TupleHash256_ElementsDoNotBleedTogether(t *testing.T) {
+	abC := passcode.SHA3TupleHash256([][]byte{[]byte("ab"), []byte("c")}, nil, 32)
+	aBC := passcode.SHA3TupleHash256([][]byte{[]byte("a"), []byte("bc")}, nil, 32)
+
+	if bytes.Equal(abC, aBC) {
+		t.Fatal("SHA3TupleHash256: (\"ab\",\"c\") and (\"a\",\"bc\") collided")
+	}
+}
+
+func Test_SHA3TupleHash128_ElementsDoNotBleedTogether(t *testing.T) {
+	abC := passcode.SHA3TupleHash128([][]byte{[]byte("ab"), []byte("c")}, nil, 32)
+	aBC := passcode.SHA3TupleHash128([][]byte{[]byte("a"), []byte("bc")}, nil, 32)
+
+	if bytes.Equal(abC, aBC) {
+		t.Fatal("SHA3TupleHash128: (\"ab\",\"c\") and (\"a\",\"bc\") collided")
+	}
+}
+
+func Test_Passcode_TupleHashAlgorithms(t *testing.T) {
+	key := make([]byte, 32)
+	challenge := []byte("challenge data")
+
+	for _, algo := range []passcode.Algorithm{
+		passcode.AlgorithmSHA3TupleHash128,
+		passcode.AlgorithmSHA3TupleHash256,
+	} {
+		pc, err := passcode.NewPasscode(algo, key)
+		if err != nil {
+			t.Fatalf("NewPasscode(%s): unexpected error: %v", algo, err)
+		}
+		if otp := pc.Compute(challenge); len(otp) == 0 {
+			t.Fatalf("Compute(%s): expected non-empty output", algo)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package passcode
+
+import "io"
+
+// PasscodeWriter lets a large challenge be MACed incrementally over several
+// Write calls instead of held in memory for one-shot Compute.
+type PasscodeWriter interface {
+	io.Writer
+	Sum(dst []byte) []byte
+	Reset()
+}
+
+// NewComputer returns an incremental PasscodeWriter for this Passcode's
+// algorithm and key, sized to match what Compute's one-shot hasher would
+// produce. It is built on NewStream, so BLAKE3 keyed mode drives its
+// incremental hash.Hash directly and KMAC drives the underlying SHAKE
+// sponge a Write at a time; only those algorithms support it, and others
+// cause a panic, same as NewStream.
+func (p *Passcode) NewComputer() PasscodeWriter {
+	return p.NewStream(p.key, nil, p.computeOutputLen())
+}
+
+// computeOutputLen reports the output length Compute's hasher produces for
+// p's algorithm, so NewComputer's incremental path matches it exactly.
+func (p *Passcode) computeOutputLen() int {
+	switch p.algorithm {
+	case string(AlgorithmBLAKE3KeyedMode256):
+		return 64
+	default:
+		return 32
+	}
+}
@@ -0,0 +1,93 @@
+package passcode_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+)
+
+func Test_NewComputer_MatchesOneShotComputeAcrossChunkBoundaries(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	challenge := make([]byte, 400)
+	rand.Read(challenge)
+
+	for _, algo := range []passcode.Algorithm{
+		passcode.AlgorithmSHA3KMAC128,
+		passcode.AlgorithmSHA3KMAC256,
+		passcode.AlgorithmBLAKE3KeyedMode128,
+		passcode.AlgorithmBLAKE3KeyedMode256,
+	} {
+		pc, err := passcode.NewPasscode(algo, key)
+		if err != nil {
+			t.Fatalf("NewPasscode(%s): unexpected error: %v", algo, err)
+		}
+		want := pc.Compute(challenge)
+
+		for _, chunkSize := range []int{1, 7, 64, 135, 136, 137, 167, 168, 169, 400} {
+			computer := pc.NewComputer()
+			for i := 0; i < len(challenge); i += chunkSize {
+				end := i + chunkSize
+				if end > len(challenge) {
+					end = len(challenge)
+				}
+				if _, err := computer.Write(challenge[i:end]); err != nil {
+					t.Fatalf("%s chunkSize=%d: Write: unexpected error: %v", algo, chunkSize, err)
+				}
+			}
+			got := computer.Sum(nil)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%s chunkSize=%d: got %x, want %x", algo, chunkSize, got, want)
+			}
+		}
+	}
+}
+
+func Test_NewComputer_ResetAllowsReuse(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmBLAKE3KeyedMode256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	computer := pc.NewComputer()
+	computer.Write([]byte("first challenge"))
+	first := computer.Sum(nil)
+
+	computer.Reset()
+	computer.Write([]byte("second challenge"))
+	second := computer.Sum(nil)
+
+	if bytes.Equal(first, second) {
+		t.Fatal("NewComputer: expected different challenges to produce different sums after Reset")
+	}
+
+	computer.Reset()
+	computer.Write([]byte("first challenge"))
+	again := computer.Sum(nil)
+	if !bytes.Equal(first, again) {
+		t.Fatal("NewComputer: expected Reset to let the same input reproduce the original sum")
+	}
+}
+
+func Test_NewComputer_PanicsForUnsupportedAlgorithm(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	pc, err := passcode.NewPasscode(passcode.AlgorithmSHA3TupleHash256, key)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewComputer: expected panic for an algorithm without streaming support")
+		}
+	}()
+	pc.NewComputer()
+}
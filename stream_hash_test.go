@@ -0,0 +1,135 @@
+package passcode_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/snowmerak/passcode"
+)
+
+// Test that streaming KMAC128 matches the one-shot function for the same input.
+func Test_NewKMAC128_MatchesOneShot(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("stream-test")
+	data := []byte("streamed message body")
+
+	expected := passcode.SHA3KMAC128(key, customization, data, 32)
+
+	h := passcode.NewKMAC128(key, customization, 32)
+	h.Write(data)
+	got := h.Sum(nil)
+
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("NewKMAC128: streaming result %x does not match one-shot %x", got, expected)
+	}
+}
+
+// Test that streaming KMAC256 written in multiple chunks matches a single write.
+func Test_NewKMAC256_ChunkedWritesMatchSingleWrite(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("chunk-test")
+	data := []byte("this message is written in several small chunks")
+
+	single := passcode.NewKMAC256(key, customization, 32)
+	single.Write(data)
+	expected := single.Sum(nil)
+
+	chunked := passcode.NewKMAC256(key, customization, 32)
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		chunked.Write(data[i:end])
+	}
+	got := chunked.Sum(nil)
+
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("NewKMAC256: chunked result %x does not match single-write %x", got, expected)
+	}
+}
+
+// Test that Reset on a streaming KMAC hash clears previously written data.
+func Test_NewKMAC256_Reset(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("reset-test")
+
+	h := passcode.NewKMAC256(key, customization, 32)
+	h.Write([]byte("first message"))
+	first := h.Sum(nil)
+
+	h.Reset()
+	h.Write([]byte("second message"))
+	second := h.Sum(nil)
+
+	if bytes.Equal(first, second) {
+		t.Fatal("NewKMAC256: Reset did not clear previous state")
+	}
+}
+
+// Test that streaming BLAKE3 keyed mode matches the one-shot function.
+func Test_NewBLAKE3Keyed_MatchesOneShot(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	data := []byte("streamed blake3 message")
+
+	expected := passcode.BLAKE3KeyedMode256(key, data)
+
+	h := passcode.NewBLAKE3Keyed(key, 32)
+	h.Write(data)
+	got := h.Sum(nil)
+
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("NewBLAKE3Keyed: streaming result %x does not match one-shot %x", got, expected)
+	}
+}
+
+// Test that the KMAC XOF variant can squeeze additional output beyond Size().
+func Test_NewKMAC128_XOF(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("xof-test")
+
+	h := passcode.NewKMAC128(key, customization, 32)
+	h.Write([]byte("xof message"))
+
+	xofHash, ok := h.(passcode.XOFHash)
+	if !ok {
+		t.Fatal("NewKMAC128: result does not implement XOFHash")
+	}
+
+	extra := make([]byte, 64)
+	if _, err := xofHash.XOF().Read(extra); err != nil {
+		t.Fatalf("XOF: unexpected read error: %v", err)
+	}
+
+	if bytes.Equal(extra, make([]byte, len(extra))) {
+		t.Fatal("XOF: squeezed output was all zero")
+	}
+}
+
+// Test that the Passcode factory's NewStream dispatches to the right streaming hash.
+func Test_Passcode_NewStream(t *testing.T) {
+	pc, err := passcode.NewPasscode(passcode.AlgorithmSHA3KMAC256, nil)
+	if err != nil {
+		t.Fatalf("NewPasscode: unexpected error: %v", err)
+	}
+
+	key := make([]byte, 32)
+	rand.Read(key)
+	customization := []byte("factory-stream-test")
+	data := []byte("factory streamed message")
+
+	h := pc.NewStream(key, customization, 32)
+	h.Write(data)
+	got := h.Sum(nil)
+
+	expected := passcode.SHA3KMAC256(key, customization, data, 32)
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("NewStream: result %x does not match one-shot %x", got, expected)
+	}
+}
@@ -8,7 +8,7 @@ import (
 
 func main() {
 	key, _ := hex.DecodeString("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
-	
+
 	// Test with empty function name and customization
 	hasher := sha3.NewCShake128([]byte(""), []byte(""))
 	hasher.Write(key)